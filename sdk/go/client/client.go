@@ -0,0 +1,98 @@
+// Package client lets a caller offload wake-word detection and intent inference to a remote Picovoice server
+// (see the sibling picovoice/server package), streaming audio out and receiving events back, over either gRPC
+// or a WebSocket. It mirrors the callback shape of picovoice.Picovoice so remote and local Picovoice usage
+// look the same to application code.
+package client
+
+import (
+	"context"
+
+	"github.com/Picovoice/picovoice/sdk/go"
+	"github.com/Picovoice/picovoice/sdk/go/server/picovoicepb"
+	rhn "github.com/Picovoice/rhino/binding/go"
+	"google.golang.org/grpc"
+)
+
+// GRPCClient streams audio to a remote Picovoice server over gRPC and dispatches the resulting events to
+// user-provided callbacks. WakeWordCallbacks is indexed by keyword the same way picovoice.Picovoice's slices are;
+// see InferenceCallbacks for how inferences are dispatched.
+type GRPCClient struct {
+	// WakeWordCallbacks[i] is invoked when the server reports a WakeWordDetected event with keyword index i.
+	WakeWordCallbacks []picovoice.WakeWordCallbackType
+
+	// InferenceCallbacks[0] is invoked for every Inference event the server reports. The Inference message
+	// carries no keyword index, so entries past index 0 are never invoked.
+	InferenceCallbacks []picovoice.InferenceCallbackType
+
+	stream picovoicepb.Picovoice_ProcessAudioClient
+	cancel context.CancelFunc
+	errCh  chan error
+}
+
+// Dial opens a gRPC connection to addr and starts the bidirectional ProcessAudio stream.
+func Dial(ctx context.Context, addr string, dialOpts ...grpc.DialOption) (*GRPCClient, error) {
+	conn, err := grpc.DialContext(ctx, addr, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := picovoicepb.NewPicovoiceClient(conn).ProcessAudio(streamCtx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	c := &GRPCClient{
+		stream: stream,
+		cancel: func() {
+			cancel()
+			conn.Close()
+		},
+		errCh:  make(chan error, 1),
+	}
+	go c.recvLoop()
+	return c, nil
+}
+
+// SendFrame streams a single frame of pcm (length picovoice.FrameLength) to the server.
+func (c *GRPCClient) SendFrame(pcm []int16) error {
+	samples := make([]int32, len(pcm))
+	for i, s := range pcm {
+		samples[i] = int32(s)
+	}
+	return c.stream.Send(&picovoicepb.AudioFrame{Samples: samples})
+}
+
+// Close ends the stream and releases the underlying connection.
+func (c *GRPCClient) Close() error {
+	c.cancel()
+	return c.stream.CloseSend()
+}
+
+func (c *GRPCClient) recvLoop() {
+	for {
+		event, err := c.stream.Recv()
+		if err != nil {
+			c.errCh <- err
+			return
+		}
+
+		if wwd := event.GetWakeWordDetected(); wwd != nil {
+			index := int(wwd.KeywordIndex)
+			if index < len(c.WakeWordCallbacks) && c.WakeWordCallbacks[index] != nil {
+				c.WakeWordCallbacks[index]()
+			}
+		}
+
+		if inference := event.GetInference(); inference != nil {
+			if len(c.InferenceCallbacks) > 0 && c.InferenceCallbacks[0] != nil {
+				c.InferenceCallbacks[0](rhn.RhinoInference{
+					IsUnderstood: inference.IsUnderstood,
+					Intent:       inference.Intent,
+					Slots:        inference.Slots,
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/Picovoice/picovoice/sdk/go"
+	rhn "github.com/Picovoice/rhino/binding/go"
+	"github.com/gorilla/websocket"
+)
+
+// wsEvent mirrors server.WSEvent; it is duplicated here rather than imported, so this package does not
+// depend on the server package.
+type wsEvent struct {
+	WakeWordDetected *struct {
+		KeywordIndex int `json:"keywordIndex"`
+	} `json:"wakeWordDetected,omitempty"`
+	Inference *struct {
+		IsUnderstood bool              `json:"isUnderstood"`
+		Intent       string            `json:"intent"`
+		Slots        map[string]string `json:"slots"`
+	} `json:"inference,omitempty"`
+}
+
+// WSClient streams audio to a remote Picovoice server over a WebSocket connection, for gateways (e.g. a
+// browser PWA) that can't easily speak gRPC.
+type WSClient struct {
+	WakeWordCallbacks  []picovoice.WakeWordCallbackType
+	InferenceCallbacks []picovoice.InferenceCallbackType
+
+	conn  *websocket.Conn
+	errCh chan error
+}
+
+// DialWebSocket opens a WebSocket connection to url (e.g. "ws://host:port/picovoice").
+func DialWebSocket(url string) (*WSClient, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &WSClient{conn: conn, errCh: make(chan error, 1)}
+	go c.recvLoop()
+	return c, nil
+}
+
+// SendFrame streams a single frame of pcm (length picovoice.FrameLength) to the server as a binary message.
+func (c *WSClient) SendFrame(pcm []int16) error {
+	data := make([]byte, len(pcm)*2)
+	for i, s := range pcm {
+		binary.LittleEndian.PutUint16(data[i*2:i*2+2], uint16(s))
+	}
+	return c.conn.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// Close ends the connection.
+func (c *WSClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *WSClient) recvLoop() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.errCh <- err
+			return
+		}
+
+		var event wsEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			continue
+		}
+
+		if event.WakeWordDetected != nil {
+			index := event.WakeWordDetected.KeywordIndex
+			if index < len(c.WakeWordCallbacks) && c.WakeWordCallbacks[index] != nil {
+				c.WakeWordCallbacks[index]()
+			}
+		}
+
+		if event.Inference != nil {
+			if len(c.InferenceCallbacks) > 0 && c.InferenceCallbacks[0] != nil {
+				c.InferenceCallbacks[0](rhn.RhinoInference{
+					IsUnderstood: event.Inference.IsUnderstood,
+					Intent:       event.Inference.Intent,
+					Slots:        event.Inference.Slots,
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package picovoice
+
+import (
+	"sync"
+
+	ppn "github.com/Picovoice/porcupine/binding/go"
+	rhn "github.com/Picovoice/rhino/binding/go"
+)
+
+// ContextCache keeps a small LRU of pre-initialized Rhino instances keyed by context path, so that a
+// SetContext call switching back to a context it has already seen skips the Init cost entirely. It is safe for
+// concurrent use.
+type ContextCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    []string
+	entries  map[string]*rhn.Rhino
+}
+
+// NewContextCache returns a ContextCache holding at most capacity entries. capacity must be greater than zero.
+func NewContextCache(capacity int) *ContextCache {
+	return &ContextCache{
+		capacity: capacity,
+		entries:  make(map[string]*rhn.Rhino),
+	}
+}
+
+// get removes and returns the cached Rhino instance for path, if any. The entry is removed rather than merely
+// marked most-recently-used because the caller is about to make it the active engine for a keyword/context
+// index; leaving it in entries too would give the same *rhn.Rhino two owners (picovoice.rhinos and the cache),
+// and Delete would then double-delete the native engine.
+func (c *ContextCache) get(path string) (*rhn.Rhino, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rhino, ok := c.entries[path]
+	if ok {
+		delete(c.entries, path)
+		c.removeFromOrder(path)
+	}
+	return rhino, ok
+}
+
+// put inserts rhino under path, evicting and returning the least-recently-used entry if the cache is full.
+// evicted is nil if nothing had to be evicted.
+func (c *ContextCache) put(path string, rhino *rhn.Rhino) (evicted *rhn.Rhino) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.entries[path]; ok && existing != rhino {
+		evicted = existing
+	}
+
+	c.entries[path] = rhino
+	c.touch(path)
+
+	if len(c.entries) > c.capacity {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		if oldest != path {
+			evicted = c.entries[oldest]
+		}
+		delete(c.entries, oldest)
+	}
+	return evicted
+}
+
+// removeFromOrder deletes path from the recency list. Callers must hold c.mu.
+func (c *ContextCache) removeFromOrder(path string) {
+	for i, p := range c.order {
+		if p == path {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// touch marks path as most-recently-used. Callers must hold c.mu.
+func (c *ContextCache) touch(path string) {
+	c.removeFromOrder(path)
+	c.order = append(c.order, path)
+}
+
+// drain empties the cache and returns every engine it held, for Delete to tear down.
+func (c *ContextCache) drain() []*rhn.Rhino {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rhinos := make([]*rhn.Rhino, 0, len(c.entries))
+	for _, rhino := range c.entries {
+		rhinos = append(rhinos, rhino)
+	}
+	c.entries = nil
+	c.order = nil
+	return rhinos
+}
+
+// SetContext swaps the Rhino context backing the currently active keyword (picovoice.activeContext while a
+// wake word is being followed up on, or keyword index 0 otherwise) for the one at path, without recreating
+// Porcupine or any other Rhino instance. If ContextCache is set and already holds an engine for path, that
+// engine is reused instead of paying Init again; the engine being replaced is handed to the cache (which, if
+// full, deletes its least-recently-used entry), or deleted if ContextCache is unset. The swap is atomic with respect to Process: an in-flight call sees either the
+// old engine for its whole frame or the new one, never a mix of the two.
+func (picovoice *Picovoice) SetContext(path string, sensitivity float32) error {
+	index := picovoice.targetIndex()
+
+	var next *rhn.Rhino
+	if picovoice.ContextCache != nil {
+		if cached, ok := picovoice.ContextCache.get(path); ok {
+			next = cached
+		}
+	}
+
+	if next == nil {
+		rhino := &rhn.Rhino{
+			ModelPath:   picovoice.RhinoModelPath,
+			ContextPath: path,
+			Sensitivity: sensitivity,
+		}
+		if err := rhino.Init(); err != nil {
+			return err
+		}
+		next = rhino
+	}
+
+	picovoice.mu.Lock()
+	defer picovoice.mu.Unlock()
+
+	previous := picovoice.rhinos[index]
+	previousPath := picovoice.ContextPaths[index]
+	picovoice.rhinos[index] = next
+	picovoice.ContextPaths[index] = path
+	picovoice.RhinoSensitivity = sensitivity
+	picovoice.ContextInfo = next.ContextInfo
+
+	if previous != nil && previous != next {
+		if picovoice.ContextCache != nil {
+			if evicted := picovoice.ContextCache.put(previousPath, previous); evicted != nil {
+				if err := evicted.Delete(); err != nil {
+					return err
+				}
+			}
+		} else if err := previous.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetKeyword swaps the Porcupine keyword backing the currently active index (picovoice.activeContext while a
+// wake word is being followed up on, or index 0 otherwise) for the one at path, without recreating any Rhino
+// instance. Porcupine only exposes a single Init call over its whole keyword set, so this necessarily
+// reinitializes the Porcupine engine as a unit; the swap is still atomic with respect to Process.
+func (picovoice *Picovoice) SetKeyword(path string, sensitivity float32) error {
+	index := picovoice.targetIndex()
+
+	keywordPaths := append([]string(nil), picovoice.KeywordPaths...)
+	sensitivities := append([]float32(nil), picovoice.PorcupineSensitivities...)
+	keywordPaths[index] = path
+	sensitivities[index] = sensitivity
+
+	next := ppn.Porcupine{
+		ModelPath:     picovoice.PorcupineModelPath,
+		KeywordPaths:  keywordPaths,
+		Sensitivities: sensitivities,
+	}
+	if err := next.Init(); err != nil {
+		return err
+	}
+
+	picovoice.mu.Lock()
+	defer picovoice.mu.Unlock()
+
+	previous := picovoice.porcupine
+	picovoice.porcupine = next
+	picovoice.KeywordPaths = keywordPaths
+	picovoice.PorcupineSensitivities = sensitivities
+
+	if err := previous.Delete(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// targetIndex returns the keyword/context index SetContext and SetKeyword operate on: the one currently active
+// while a wake word is being followed up on, or index 0 otherwise.
+func (picovoice *Picovoice) targetIndex() int {
+	picovoice.mu.Lock()
+	defer picovoice.mu.Unlock()
+
+	if picovoice.wakeWordDetected {
+		return picovoice.activeContext
+	}
+	return 0
+}
@@ -0,0 +1,340 @@
+package picovoice
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// AudioSource produces consecutive frames of 16-bit linearly-encoded, single-channel audio at SampleRateHz.
+type AudioSource interface {
+	// SampleRateHz returns the sample rate the source produces audio at.
+	SampleRateHz() int
+
+	// ReadFrame fills frame (length FrameLength) with the next frame of audio. It returns io.EOF once the
+	// source is exhausted.
+	ReadFrame(frame []int16) error
+
+	// Close releases any resources held by the source.
+	Close() error
+}
+
+// StreamListener pumps audio from an AudioSource into a Picovoice instance's Process method, running the pump
+// loop on its own goroutine. It is the always-listening counterpart to calling Process directly: most callers
+// should prefer it over assembling their own capture loop.
+type StreamListener struct {
+	// Picovoice instance to feed captured audio into. Must already be Init'd.
+	Picovoice *Picovoice
+
+	// Source of audio frames. If nil, Start opens the default PortAudio input device.
+	Source AudioSource
+
+	// Frames, if non-nil, receives a copy of each frame read from Source. It is intended for debugging or
+	// recording. The send does not block: if the channel is full, that frame's copy is dropped and the pump
+	// loop carries on.
+	Frames chan []int16
+
+	cancel  context.CancelFunc
+	done    chan error
+	running bool
+}
+
+// Start begins pumping audio into Process until ctx is cancelled, Stop is called, or the source is exhausted
+// or errors. It returns once the pump goroutine has been launched; call Stop (or cancel ctx) and then wait on
+// the error returned by Stop to know when the loop has actually exited.
+func (l *StreamListener) Start(ctx context.Context) error {
+	if l.running {
+		return fmt.Errorf("StreamListener is already running")
+	}
+
+	if l.Source == nil {
+		source, err := newPortAudioSource()
+		if err != nil {
+			return err
+		}
+		l.Source = source
+	}
+
+	if l.Source.SampleRateHz() != SampleRate {
+		l.Source = newResamplingSource(l.Source, SampleRate)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	l.cancel = cancel
+	l.done = make(chan error, 1)
+	l.running = true
+
+	go l.run(runCtx)
+	return nil
+}
+
+func (l *StreamListener) run(ctx context.Context) {
+	frame := make([]int16, FrameLength)
+	for {
+		select {
+		case <-ctx.Done():
+			l.done <- nil
+			return
+		default:
+		}
+
+		if err := l.Source.ReadFrame(frame); err != nil {
+			if err == io.EOF {
+				l.done <- nil
+			} else {
+				l.done <- err
+			}
+			return
+		}
+
+		if l.Frames != nil {
+			cp := make([]int16, len(frame))
+			copy(cp, frame)
+			select {
+			case l.Frames <- cp:
+			default:
+			}
+		}
+
+		if err := l.Picovoice.Process(frame); err != nil {
+			l.done <- err
+			return
+		}
+	}
+}
+
+// Stop signals the pump loop to exit, closes the audio source, and returns any error the loop exited with.
+func (l *StreamListener) Stop() error {
+	if !l.running {
+		return nil
+	}
+
+	l.cancel()
+	err := <-l.done
+	l.running = false
+
+	if closeErr := l.Source.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// InputDevice describes a PortAudio input-capable device, as returned by ListInputDevices.
+type InputDevice struct {
+	Name              string
+	MaxInputChannels  int
+	DefaultSampleRate float64
+}
+
+// ListInputDevices enumerates the audio input devices visible to PortAudio on the host machine.
+func ListInputDevices() ([]InputDevice, error) {
+	if err := portaudio.Initialize(); err != nil {
+		return nil, err
+	}
+	defer portaudio.Terminate()
+
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs []InputDevice
+	for _, d := range devices {
+		if d.MaxInputChannels <= 0 {
+			continue
+		}
+		inputs = append(inputs, InputDevice{
+			Name:              d.Name,
+			MaxInputChannels:  d.MaxInputChannels,
+			DefaultSampleRate: d.DefaultSampleRate,
+		})
+	}
+	return inputs, nil
+}
+
+type portAudioSource struct {
+	stream *portaudio.Stream
+	buffer []int16
+}
+
+func newPortAudioSource() (*portAudioSource, error) {
+	if err := portaudio.Initialize(); err != nil {
+		return nil, err
+	}
+
+	buffer := make([]int16, FrameLength)
+	stream, err := portaudio.OpenDefaultStream(1, 0, float64(SampleRate), FrameLength, buffer)
+	if err != nil {
+		portaudio.Terminate()
+		return nil, err
+	}
+
+	if err := stream.Start(); err != nil {
+		stream.Close()
+		portaudio.Terminate()
+		return nil, err
+	}
+
+	return &portAudioSource{stream: stream, buffer: buffer}, nil
+}
+
+func (s *portAudioSource) SampleRateHz() int {
+	return SampleRate
+}
+
+func (s *portAudioSource) ReadFrame(frame []int16) error {
+	if err := s.stream.Read(); err != nil {
+		return err
+	}
+	copy(frame, s.buffer)
+	return nil
+}
+
+func (s *portAudioSource) Close() error {
+	stopErr := s.stream.Stop()
+	closeErr := s.stream.Close()
+	portaudio.Terminate()
+
+	if stopErr != nil {
+		return stopErr
+	}
+	return closeErr
+}
+
+// WAVFileSource replays a 16kHz, mono, 16-bit PCM WAV file as a sequence of frames, primarily for feeding
+// fixtures into a Picovoice instance in tests without requiring a live microphone.
+type WAVFileSource struct {
+	file       *os.File
+	sampleRate int
+}
+
+// NewWAVFileSource opens path, which must be a 16-bit PCM WAV file, and validates its format before returning.
+func NewWAVFileSource(path string) (*WAVFileSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sampleRate, err := readWAVHeader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &WAVFileSource{file: f, sampleRate: sampleRate}, nil
+}
+
+func (s *WAVFileSource) SampleRateHz() int {
+	return s.sampleRate
+}
+
+func (s *WAVFileSource) ReadFrame(frame []int16) error {
+	raw := make([]byte, len(frame)*2)
+	if _, err := io.ReadFull(s.file, raw); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return io.EOF
+		}
+		return err
+	}
+
+	for i := range frame {
+		frame[i] = int16(binary.LittleEndian.Uint16(raw[i*2 : i*2+2]))
+	}
+	return nil
+}
+
+func (s *WAVFileSource) Close() error {
+	return s.file.Close()
+}
+
+// readWAVHeader parses the minimum of a canonical WAV header needed to validate and report the sample rate,
+// leaving the file positioned at the start of the "data" chunk's samples.
+func readWAVHeader(f *os.File) (int, error) {
+	header := make([]byte, 44)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return 0, fmt.Errorf("not a valid WAV file: %v", err)
+	}
+
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return 0, fmt.Errorf("not a valid WAV file: missing RIFF/WAVE header")
+	}
+
+	numChannels := binary.LittleEndian.Uint16(header[22:24])
+	if numChannels != 1 {
+		return 0, fmt.Errorf("WAV file must be mono, got %d channels", numChannels)
+	}
+
+	bitsPerSample := binary.LittleEndian.Uint16(header[34:36])
+	if bitsPerSample != 16 {
+		return 0, fmt.Errorf("WAV file must be 16-bit, got %d bits per sample", bitsPerSample)
+	}
+
+	sampleRate := int(binary.LittleEndian.Uint32(header[24:28]))
+	return sampleRate, nil
+}
+
+// resamplingSource wraps an AudioSource that produces audio at a different sample rate than SampleRate,
+// converting it via simple linear interpolation so Picovoice always receives FrameLength frames at SampleRate.
+type resamplingSource struct {
+	inner      AudioSource
+	targetRate int
+	leftover   []int16
+}
+
+func newResamplingSource(inner AudioSource, targetRate int) *resamplingSource {
+	return &resamplingSource{inner: inner, targetRate: targetRate}
+}
+
+func (s *resamplingSource) SampleRateHz() int {
+	return s.targetRate
+}
+
+func (s *resamplingSource) ReadFrame(frame []int16) error {
+	ratio := float64(s.inner.SampleRateHz()) / float64(s.targetRate)
+
+	// inner.ReadFrame must be called with a FrameLength-sized buffer: the AudioSource contract guarantees
+	// sources fill exactly FrameLength samples per call (at their own native rate), and portAudioSource in
+	// particular ignores the slice length entirely and always reads one such block. Reading any other length
+	// from it would silently lose or misalign samples.
+	raw := make([]int16, FrameLength)
+	for len(s.leftover) < len(frame) {
+		if err := s.inner.ReadFrame(raw); err != nil {
+			return err
+		}
+		s.leftover = append(s.leftover, resampleLinear(raw, ratio)...)
+	}
+
+	copy(frame, s.leftover[:len(frame)])
+	s.leftover = s.leftover[len(frame):]
+	return nil
+}
+
+func (s *resamplingSource) Close() error {
+	return s.inner.Close()
+}
+
+// resampleLinear converts samples recorded at a rate `ratio` times the target rate down (or up) to the target
+// rate using linear interpolation between neighbouring samples.
+func resampleLinear(samples []int16, ratio float64) []int16 {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	outLen := int(float64(len(samples)) / ratio)
+	out := make([]int16, outLen)
+	for i := range out {
+		srcPos := float64(i) * ratio
+		idx := int(srcPos)
+		if idx >= len(samples)-1 {
+			out[i] = samples[len(samples)-1]
+			continue
+		}
+		frac := srcPos - float64(idx)
+		out[i] = int16(float64(samples[idx])*(1-frac) + float64(samples[idx+1])*frac)
+	}
+	return out
+}
@@ -12,9 +12,10 @@
 // Go binding for Picovoice end-to-end platform. Picovoice enables building voice experiences similar to Alexa but
 // runs entirely on-device (offline).
 
-// Picovoice detects utterances of a customizable wake word (phrase) within an incoming stream of audio in real-time.
-// After detection of wake word, it begins to infer the user's intent from the follow-on spoken command. Upon detection
-// of wake word and completion of voice command, it invokes user-provided callbacks to signal these events.
+// Picovoice detects utterances of one or more customizable wake words (phrases) within an incoming stream of audio
+// in real-time. After detection of a wake word, it begins to infer the user's intent from the follow-on spoken
+// command using the Rhino context associated with that wake word. Upon detection of a wake word and completion of
+// the voice command, it invokes user-provided callbacks to signal these events.
 
 // Picovoice processes incoming audio in consecutive frames. The number of samples per frame is
 // `FrameLength`. The incoming audio needs to have a sample rate equal to `SampleRate` and be 16-bit
@@ -26,9 +27,11 @@ package picovoice
 import (
 	"fmt"
 	"os"
+	"sync"
 
 	ppn "github.com/Picovoice/porcupine/binding/go"
 	rhn "github.com/Picovoice/rhino/binding/go"
+	webrtcvad "github.com/maxhawkins/go-webrtcvad"
 )
 
 // PvStatus descibes error codes returned from native code
@@ -73,63 +76,132 @@ type InferenceCallbackType func(rhn.RhinoInference)
 
 // Picovoice struct
 type Picovoice struct {
-	// instance of porcupine
+	// guards every field Process/Reset/SetContext/SetKeyword read or mutate, so that a SetContext/SetKeyword
+	// swap and an in-flight Process/Reset call never interleave. It is a plain Mutex, not a RWMutex: Process and
+	// Reset both mutate state (wakeWordDetected, commandFrameCount, activeContext, rhinos, ContextInfo), so
+	// letting them run concurrently under RLock would race. User callbacks are always invoked with mu released
+	// (see processLocked), so a callback that calls back into Reset does not self-deadlock.
+	mu sync.Mutex
+
+	// instance of porcupine, configured with all of KeywordPaths
 	porcupine ppn.Porcupine
 
-	// instance of rhino
-	rhino rhn.Rhino
+	// instances of rhino, lazily initialized and cached by keyword/context index
+	rhinos map[int]*rhn.Rhino
+
+	// index of the keyword whose context is currently active, valid only while wakeWordDetected is true
+	activeContext int
+
+	// number of frames of pcm processed by the active Rhino context since the wake word was detected
+	commandFrameCount int
+
+	// instance of the voice-activity detector, lazily initialized on first use if EnableVAD is true
+	vad *webrtcvad.VAD
+
+	// rolling buffer of the most recent VADPrerollFrames non-speech frames, flushed into Porcupine once speech
+	// resumes so the onset of the wake word isn't clipped by the gate
+	vadPreroll [][]int16
 
 	// only true after init and before delete
 	initialized bool
 
-	// true after Porcupine detected wake word
+	// true after Porcupine detected a wake word
 	wakeWordDetected bool
 
-	// Path to Porcupine keyword file (.ppn)
-	KeywordPath string
+	// Paths to Porcupine keyword files (.ppn), one per wake word
+	KeywordPaths []string
+
+	// Sensitivity values for detecting each keyword in KeywordPaths. Each value should be a number within [0, 1]. A
+	// higher sensitivity results in fewer misses at the cost of increasing the false alarm rate. Must be empty
+	// (Init then uses 0.5 for every keyword) or the same length as KeywordPaths.
+	PorcupineSensitivities []float32
+
+	// Functions to be called once the corresponding keyword in KeywordPaths has been detected. Must be empty or
+	// the same length as KeywordPaths; nil entries are skipped.
+	WakeWordCallbacks []WakeWordCallbackType
+
+	// Paths to Rhino context files (.rhn), one per wake word. ContextPaths[i] is activated once KeywordPaths[i] is
+	// detected. Must be the same length as KeywordPaths.
+	ContextPaths []string
 
-	// Function to be called once the wake word has been detected
-	WakeWordCallback WakeWordCallbackType
+	// Functions to be called once Rhino has an inference ready for the corresponding context in ContextPaths. Must
+	// be the same length as KeywordPaths.
+	InferenceCallbacks []InferenceCallbackType
 
-	// Path to Rhino context file (.rhn)
-	ContextPath string
+	// If greater than zero, Process automatically calls Reset if Rhino has not finalized an inference within
+	// CommandTimeoutMs milliseconds of the wake word being detected. A value of zero disables the timeout.
+	CommandTimeoutMs int
 
-	// Function to be called once Rhino has an inference ready
-	InferenceCallback InferenceCallbackType
+	// Called on every frame while a command is being inferred (i.e. between wake word detection and the
+	// resulting inference, or a Reset/timeout), with the number of milliseconds elapsed since the wake word was
+	// detected. Intended for UIs that want to render a "listening..." indicator or progress bar.
+	PartialCallback func(elapsedMs int)
+
+	// If true, frames are passed through a voice-activity detector before being handed to Porcupine, and frames
+	// classified as non-speech are skipped. This cuts the idle CPU cost of always-listening for a wake word at
+	// the expense of a small amount of latency on speech onset, which VADPrerollFrames offsets.
+	EnableVAD bool
+
+	// Aggressiveness of the voice-activity detector, within [0, 3]. Higher values are more aggressive about
+	// filtering out non-speech, at the cost of being more likely to clip quiet speech.
+	VADAggressiveness int
+
+	// Number of trailing non-speech frames to cache and replay through Porcupine as soon as speech resumes, so
+	// the detector's onset lag doesn't clip the start of the wake word.
+	VADPrerollFrames int
 
 	// Path to Porcupine model file (.pv)
 	PorcupineModelPath string
 
-	// Sensitivity value for detecting keyword. The value should be a number within [0, 1]. A
-	// higher sensitivity results in fewer misses at the cost of increasing the false alarm rate.
-	PorcupineSensitivity float32
-
 	// Path to Rhino model file (.pv)
 	RhinoModelPath string
 
-	// Inference sensitivity. A higher sensitivity value results in
+	// Inference sensitivity, shared across all Rhino contexts. A higher sensitivity value results in
 	// fewer misses at the cost of (potentially) increasing the erroneous inference rate.
 	// Sensitivity should be a floating-point number within 0 and 1.
 	RhinoSensitivity float32
 
-	// Once initialized, stores the source of the Rhino context in YAML format. Shows the list of intents,
-	// which expressions map to those intents, as well as slots and their possible values.
+	// Once a context has been activated, stores the source of its Rhino context in YAML format. Shows the list of
+	// intents, which expressions map to those intents, as well as slots and their possible values.
 	ContextInfo string
+
+	// If set, SetContext consults it before initializing a new Rhino engine, and hands it the engine being
+	// replaced, so that switching back and forth between a small set of contexts avoids repeated Init cost.
+	ContextCache *ContextCache
 }
 
-// Returns a Picovoice stuct with default parameters
+// Returns a Picovoice struct listening for a single wake word and routing to a single Rhino context.
 func NewPicovoice(keywordPath string,
 	wakewordCallback WakeWordCallbackType,
 	contextPath string,
 	inferenceCallback InferenceCallbackType) Picovoice {
+	return NewPicovoiceMulti(
+		[]string{keywordPath},
+		[]WakeWordCallbackType{wakewordCallback},
+		[]string{contextPath},
+		[]InferenceCallbackType{inferenceCallback})
+}
+
+// Returns a Picovoice struct able to listen for several wake words concurrently, routing each to its own Rhino
+// context. keywordPaths, wakewordCallbacks, contextPaths and inferenceCallbacks must all be the same length, with
+// entry i of each slice describing the i-th wake word.
+func NewPicovoiceMulti(keywordPaths []string,
+	wakewordCallbacks []WakeWordCallbackType,
+	contextPaths []string,
+	inferenceCallbacks []InferenceCallbackType) Picovoice {
+	sensitivities := make([]float32, len(keywordPaths))
+	for i := range sensitivities {
+		sensitivities[i] = 0.5
+	}
+
 	return Picovoice{
-		KeywordPath:       keywordPath,
-		WakeWordCallback:  wakewordCallback,
-		ContextPath:       contextPath,
-		InferenceCallback: inferenceCallback,
+		KeywordPaths:           keywordPaths,
+		WakeWordCallbacks:      wakewordCallbacks,
+		ContextPaths:           contextPaths,
+		InferenceCallbacks:     inferenceCallbacks,
+		PorcupineSensitivities: sensitivities,
 
-		PorcupineSensitivity: 0.5,
-		RhinoSensitivity:     0.5,
+		RhinoSensitivity: 0.5,
 	}
 }
 
@@ -153,24 +225,51 @@ var (
 // Init function for Picovoice. Must be called before attempting process.
 func (picovoice *Picovoice) Init() error {
 
-	if picovoice.KeywordPath == "" {
+	if len(picovoice.KeywordPaths) == 0 {
 		return fmt.Errorf("%s: No valid keyword was provided.", pvStatusToString(INVALID_ARGUMENT))
 	}
 
-	if _, err := os.Stat(picovoice.KeywordPath); os.IsNotExist(err) {
-		return fmt.Errorf("%s: Keyword file file could not be found at %s", pvStatusToString(INVALID_ARGUMENT), picovoice.KeywordPath)
+	for _, keywordPath := range picovoice.KeywordPaths {
+		if _, err := os.Stat(keywordPath); os.IsNotExist(err) {
+			return fmt.Errorf("%s: Keyword file could not be found at %s", pvStatusToString(INVALID_ARGUMENT), keywordPath)
+		}
+	}
+
+	if len(picovoice.ContextPaths) != len(picovoice.KeywordPaths) {
+		return fmt.Errorf("%s: ContextPaths (%d) must be the same length as KeywordPaths (%d).",
+			pvStatusToString(INVALID_ARGUMENT), len(picovoice.ContextPaths), len(picovoice.KeywordPaths))
+	}
+
+	for _, contextPath := range picovoice.ContextPaths {
+		if _, err := os.Stat(contextPath); os.IsNotExist(err) {
+			return fmt.Errorf("%s: Context file could not be found at %s", pvStatusToString(INVALID_ARGUMENT), contextPath)
+		}
 	}
 
-	if picovoice.ContextPath == "" {
-		return fmt.Errorf("%s: No valid context was provided.", pvStatusToString(INVALID_ARGUMENT))
+	if len(picovoice.InferenceCallbacks) != len(picovoice.KeywordPaths) {
+		return fmt.Errorf("%s: InferenceCallbacks (%d) must be the same length as KeywordPaths (%d).",
+			pvStatusToString(INVALID_ARGUMENT), len(picovoice.InferenceCallbacks), len(picovoice.KeywordPaths))
 	}
 
-	if _, err := os.Stat(picovoice.ContextPath); os.IsNotExist(err) {
-		return fmt.Errorf("%s: Context file could not be found at %s", pvStatusToString(INVALID_ARGUMENT), picovoice.ContextPath)
+	if len(picovoice.WakeWordCallbacks) != 0 && len(picovoice.WakeWordCallbacks) != len(picovoice.KeywordPaths) {
+		return fmt.Errorf("%s: WakeWordCallbacks (%d) must be the same length as KeywordPaths (%d).",
+			pvStatusToString(INVALID_ARGUMENT), len(picovoice.WakeWordCallbacks), len(picovoice.KeywordPaths))
 	}
 
-	if picovoice.InferenceCallback == nil {
-		return fmt.Errorf("%s: No InferenceCallback was provided.", pvStatusToString(INVALID_ARGUMENT))
+	for _, inferenceCallback := range picovoice.InferenceCallbacks {
+		if inferenceCallback == nil {
+			return fmt.Errorf("%s: No InferenceCallback was provided.", pvStatusToString(INVALID_ARGUMENT))
+		}
+	}
+
+	if len(picovoice.PorcupineSensitivities) == 0 {
+		picovoice.PorcupineSensitivities = make([]float32, len(picovoice.KeywordPaths))
+		for i := range picovoice.PorcupineSensitivities {
+			picovoice.PorcupineSensitivities[i] = 0.5
+		}
+	} else if len(picovoice.PorcupineSensitivities) != len(picovoice.KeywordPaths) {
+		return fmt.Errorf("%s: PorcupineSensitivities (%d) must be the same length as KeywordPaths (%d).",
+			pvStatusToString(INVALID_ARGUMENT), len(picovoice.PorcupineSensitivities), len(picovoice.KeywordPaths))
 	}
 
 	if ppn.SampleRate != rhn.SampleRate {
@@ -189,46 +288,137 @@ func (picovoice *Picovoice) Init() error {
 
 	picovoice.porcupine = ppn.Porcupine{
 		ModelPath:     picovoice.PorcupineModelPath,
-		KeywordPaths:  []string{picovoice.KeywordPath},
-		Sensitivities: []float32{0.5},
+		KeywordPaths:  picovoice.KeywordPaths,
+		Sensitivities: picovoice.PorcupineSensitivities,
 	}
 	err := picovoice.porcupine.Init()
 	if err != nil {
 		return err
 	}
 
-	picovoice.rhino = rhn.Rhino{
+	picovoice.rhinos = make(map[int]*rhn.Rhino)
+	picovoice.initialized = true
+	return nil
+}
+
+// Releases resouces aquired by Picovoice
+func (picovoice *Picovoice) Delete() error {
+	if err := picovoice.porcupine.Delete(); err != nil {
+		return err
+	}
+
+	for _, rhino := range picovoice.rhinos {
+		if err := rhino.Delete(); err != nil {
+			return err
+		}
+	}
+
+	if picovoice.ContextCache != nil {
+		for _, rhino := range picovoice.ContextCache.drain() {
+			if err := rhino.Delete(); err != nil {
+				return err
+			}
+		}
+	}
+
+	picovoice.rhinos = nil
+	picovoice.initialized = false
+	return nil
+}
+
+// rhinoForContext lazily initializes (or returns the cached instance of) the Rhino engine for keyword/context index i.
+func (picovoice *Picovoice) rhinoForContext(i int) (*rhn.Rhino, error) {
+	if rhino, ok := picovoice.rhinos[i]; ok {
+		return rhino, nil
+	}
+
+	rhino := &rhn.Rhino{
 		ModelPath:   picovoice.RhinoModelPath,
-		ContextPath: picovoice.ContextPath,
+		ContextPath: picovoice.ContextPaths[i],
 		Sensitivity: picovoice.RhinoSensitivity,
 	}
-	err = picovoice.rhino.Init()
+	if err := rhino.Init(); err != nil {
+		return nil, err
+	}
+
+	picovoice.rhinos[i] = rhino
+	return rhino, nil
+}
+
+// IsWakeWordDetected reports whether Process is currently in the command phase, i.e. a wake word has been
+// detected and Picovoice is awaiting a finalized inference from the active Rhino context.
+func (picovoice *Picovoice) IsWakeWordDetected() bool {
+	picovoice.mu.Lock()
+	defer picovoice.mu.Unlock()
+	return picovoice.wakeWordDetected
+}
+
+// Reset aborts the current Rhino utterance, if any, and returns Picovoice to wake-word listening. It is safe to
+// call even when no wake word has been detected, in which case it is a no-op. Useful for a push-to-cancel
+// control or a silence timeout driven by the caller rather than CommandTimeoutMs.
+func (picovoice *Picovoice) Reset() error {
+	picovoice.mu.Lock()
+	defer picovoice.mu.Unlock()
+	return picovoice.resetLocked()
+}
+
+// resetLocked is Reset's implementation, for callers that already hold picovoice.mu (Process, on a
+// CommandTimeoutMs expiry).
+func (picovoice *Picovoice) resetLocked() error {
+	if !picovoice.wakeWordDetected {
+		return nil
+	}
+
+	rhino, err := picovoice.rhinoForContext(picovoice.activeContext)
 	if err != nil {
 		return err
 	}
-	picovoice.ContextInfo = picovoice.rhino.ContextInfo
-	picovoice.initialized = true
+	if err := rhino.Reset(); err != nil {
+		return err
+	}
+
+	picovoice.wakeWordDetected = false
+	picovoice.commandFrameCount = 0
 	return nil
 }
 
-// Releases resouces aquired by Picovoice
-func (picovoice *Picovoice) Delete() error {
-	porcupineErr := picovoice.porcupine.Delete()
-	rhinoErr := picovoice.rhino.Delete()
+// frameDurationMs is the duration, in milliseconds, of a single frame of audio at SampleRate.
+func frameDurationMs() int {
+	return FrameLength * 1000 / SampleRate
+}
 
-	if porcupineErr != nil {
-		return porcupineErr
+// processWakeWordFrame runs a single frame through Porcupine and transitions into the command phase if a
+// keyword is detected. It is shared between the normal Process path and VAD preroll flushing. Callers must
+// hold picovoice.mu. Rather than invoking WakeWordCallbacks itself, it returns the callback (if any) to run,
+// so the caller can invoke it after releasing the lock.
+func (picovoice *Picovoice) processWakeWordFrame(pcm []int16) (callback func(), err error) {
+	keywordIndex, err := picovoice.porcupine.Process(pcm)
+	if err != nil {
+		return nil, err
 	}
-	if rhinoErr != nil {
-		return rhinoErr
+
+	if keywordIndex < 0 {
+		return nil, nil
 	}
 
-	picovoice.initialized = false
-	return nil
+	picovoice.wakeWordDetected = true
+	picovoice.activeContext = keywordIndex
+	picovoice.commandFrameCount = 0
+
+	rhino, err := picovoice.rhinoForContext(keywordIndex)
+	if err != nil {
+		return nil, err
+	}
+	picovoice.ContextInfo = rhino.ContextInfo
+
+	if keywordIndex >= len(picovoice.WakeWordCallbacks) {
+		return nil, nil
+	}
+	return picovoice.WakeWordCallbacks[keywordIndex], nil
 }
 
 // Process a frame of pcm audio with the Picovoice platform.
-// Invokes user-defined callbacks upon detection of wake word and completion of follow-on command inference
+// Invokes user-defined callbacks upon detection of a wake word and completion of the follow-on command inference
 func (picovoice *Picovoice) Process(pcm []int16) error {
 	if !picovoice.initialized {
 		return fmt.Errorf("Picovoice could not process because it has either not been initialized or has been deleted.")
@@ -238,32 +428,87 @@ func (picovoice *Picovoice) Process(pcm []int16) error {
 		return fmt.Errorf("Input data frame size (%d) does not match required size of %d", len(pcm), FrameLength)
 	}
 
+	callbacks, err := picovoice.processLocked(pcm)
+
+	// Callbacks run only after mu is released: a callback is arbitrary user code, and if it turns around and
+	// calls Reset (the push-to-cancel use case CommandTimeoutMs/PartialCallback exist for), doing that while
+	// still holding mu would deadlock.
+	for _, callback := range callbacks {
+		callback()
+	}
+	return err
+}
+
+// processLocked is Process's state machine, run under picovoice.mu. It returns the user callbacks (if any)
+// that the detected event(s) should trigger, deferred until after the caller releases the lock.
+func (picovoice *Picovoice) processLocked(pcm []int16) ([]func(), error) {
+	picovoice.mu.Lock()
+	defer picovoice.mu.Unlock()
+
+	var callbacks []func()
+
 	if !picovoice.wakeWordDetected {
-		keywordIndex, err := picovoice.porcupine.Process(pcm)
-		if err != nil {
-			return err
-		}
+		if picovoice.EnableVAD {
+			gate, flush, err := picovoice.filterVAD(pcm)
+			if err != nil {
+				return callbacks, err
+			}
+			if gate {
+				return callbacks, nil
+			}
 
-		if keywordIndex == 0 {
-			picovoice.wakeWordDetected = true
-			if picovoice.WakeWordCallback != nil {
-				picovoice.WakeWordCallback()
+			for _, flushed := range flush {
+				callback, err := picovoice.processWakeWordFrame(flushed)
+				if callback != nil {
+					callbacks = append(callbacks, callback)
+				}
+				if err != nil {
+					return callbacks, err
+				}
+				if picovoice.wakeWordDetected {
+					return callbacks, nil
+				}
 			}
 		}
-	} else {
-		isFinalized, err := picovoice.rhino.Process(pcm)
-		if err != nil {
-			return err
+
+		callback, err := picovoice.processWakeWordFrame(pcm)
+		if callback != nil {
+			callbacks = append(callbacks, callback)
 		}
-		if isFinalized {
-			picovoice.wakeWordDetected = false
-			inference, err := picovoice.rhino.GetInference()
-			if err != nil {
-				return err
-			}
+		return callbacks, err
+	}
 
-			picovoice.InferenceCallback(inference)
+	picovoice.commandFrameCount++
+	elapsedMs := picovoice.commandFrameCount * frameDurationMs()
+
+	if picovoice.CommandTimeoutMs > 0 && elapsedMs >= picovoice.CommandTimeoutMs {
+		return callbacks, picovoice.resetLocked()
+	}
+
+	if picovoice.PartialCallback != nil {
+		partialCallback := picovoice.PartialCallback
+		callbacks = append(callbacks, func() { partialCallback(elapsedMs) })
+	}
+
+	rhino, err := picovoice.rhinoForContext(picovoice.activeContext)
+	if err != nil {
+		return callbacks, err
+	}
+
+	isFinalized, err := rhino.Process(pcm)
+	if err != nil {
+		return callbacks, err
+	}
+	if isFinalized {
+		picovoice.wakeWordDetected = false
+		picovoice.commandFrameCount = 0
+		inference, err := rhino.GetInference()
+		if err != nil {
+			return callbacks, err
 		}
+
+		inferenceCallback := picovoice.InferenceCallbacks[picovoice.activeContext]
+		callbacks = append(callbacks, func() { inferenceCallback(inference) })
 	}
-	return nil
+	return callbacks, nil
 }
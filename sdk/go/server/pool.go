@@ -0,0 +1,67 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/Picovoice/picovoice/sdk/go"
+)
+
+// Pool caches initialized Picovoice engines keyed by an arbitrary string (typically a context path), so that
+// repeat connections asking for the same context/keyword pair don't pay Init's model-loading cost again. It is
+// safe for concurrent use.
+type Pool struct {
+	newPicovoice func() *picovoice.Picovoice
+
+	mu   sync.Mutex
+	idle map[string][]*picovoice.Picovoice
+}
+
+// NewPool returns a Pool that creates fresh engines with newPicovoice when the cache is empty for a key.
+func NewPool(newPicovoice func() *picovoice.Picovoice) *Pool {
+	return &Pool{
+		newPicovoice: newPicovoice,
+		idle:         make(map[string][]*picovoice.Picovoice),
+	}
+}
+
+// Get returns an idle engine cached under key if one exists, otherwise constructs and initializes a new one.
+func (p *Pool) Get(key string) (*picovoice.Picovoice, error) {
+	p.mu.Lock()
+	if queue := p.idle[key]; len(queue) > 0 {
+		pv := queue[len(queue)-1]
+		p.idle[key] = queue[:len(queue)-1]
+		p.mu.Unlock()
+		return pv, nil
+	}
+	p.mu.Unlock()
+
+	pv := p.newPicovoice()
+	if err := pv.Init(); err != nil {
+		return nil, err
+	}
+	return pv, nil
+}
+
+// Put returns pv to the pool under key for reuse by a future Get, rather than deleting it.
+func (p *Pool) Put(key string, pv *picovoice.Picovoice) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle[key] = append(p.idle[key], pv)
+}
+
+// Close tears down every idle engine cached in the pool.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var firstErr error
+	for key, queue := range p.idle {
+		for _, pv := range queue {
+			if err := pv.Delete(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		delete(p.idle, key)
+	}
+	return firstErr
+}
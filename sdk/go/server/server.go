@@ -0,0 +1,165 @@
+// Package server lets a single machine's Porcupine/Rhino models be shared by remote audio clients, so a
+// microcontroller gateway or browser PWA can offload wake-word detection and intent inference to a Pi or
+// server instead of running the native libraries itself.
+package server
+
+import (
+	"io"
+
+	"github.com/Picovoice/picovoice/sdk/go"
+	"github.com/Picovoice/picovoice/sdk/go/server/picovoicepb"
+	rhn "github.com/Picovoice/rhino/binding/go"
+)
+
+// Server implements picovoicepb.PicovoiceServer, the gRPC side of the transport. Each ProcessAudio stream gets
+// its own Picovoice instance, either freshly constructed or checked out of Pool if one was configured.
+type Server struct {
+	picovoicepb.UnimplementedPicovoiceServer
+
+	// NewPicovoice constructs an uninitialized Picovoice configured for this deployment (model paths, keywords,
+	// contexts); ProcessAudio calls Init on it. It is called once per connection unless Pool is set.
+	NewPicovoice func() *picovoice.Picovoice
+
+	// Pool, if non-nil, supplies the engine instead of NewPicovoice (Pool.Get builds one with the pool's own
+	// constructor when it has no idle engine for the key), and the engine is returned to the pool (rather than
+	// deleted) once the connection ends. The key comes from PoolKey.
+	Pool *Pool
+
+	// PoolKey extracts the pool key (typically a context path) for an incoming connection. Required if Pool is
+	// set; ignored otherwise.
+	PoolKey func(picovoicepb.Picovoice_ProcessAudioServer) string
+}
+
+// eventBacklog is the number of events ProcessAudio/WebSocketHandler will buffer for a connection before
+// dropping further events rather than blocking Process. A slow consumer only ever costs itself missed events,
+// never stalls the audio pipeline or the goroutine feeding it.
+const eventBacklog = 32
+
+// ProcessAudio implements the bidirectional streaming RPC: frames of audio in, wake-word/inference events out.
+func (s *Server) ProcessAudio(stream picovoicepb.Picovoice_ProcessAudioServer) error {
+	var key string
+	var pv *picovoice.Picovoice
+	var err error
+
+	if s.Pool != nil {
+		key = s.PoolKey(stream)
+		pv, err = s.Pool.Get(key)
+	} else {
+		pv = s.NewPicovoice()
+		err = pv.Init()
+	}
+	if err != nil {
+		return err
+	}
+
+	events := make(chan *picovoicepb.Event, eventBacklog)
+	restore := attachEventCallbacks(pv, events)
+
+	recvErr := make(chan error, 1)
+	recvDone := make(chan struct{})
+	go func() {
+		defer close(recvDone)
+		for {
+			frame, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			if err := pv.Process(samplesToInt16(frame.Samples)); err != nil {
+				recvErr <- err
+				return
+			}
+		}
+	}()
+
+	// Wait for the recv goroutine to actually exit before restoring callbacks and returning pv to the pool (or
+	// deleting it): otherwise a Process call still in flight there could run concurrently with Pool.Put/Delete.
+	defer func() {
+		<-recvDone
+		restore()
+		if s.Pool != nil {
+			// A connection can end mid-command (client disconnects, or just never finishes an utterance); without
+			// this, the next connection to check pv out of the pool would have its audio fed to the stale active
+			// Rhino context instead of Porcupine until that context finalizes or times out.
+			pv.Reset()
+			s.Pool.Put(key, pv)
+		} else {
+			pv.Delete()
+		}
+	}()
+
+	for {
+		select {
+		case event := <-events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// attachEventCallbacks wraps pv's existing WakeWordCallbacks/InferenceCallbacks so that, in addition to
+// whatever the caller already configured, every detection and inference is also pushed onto events. It returns
+// a function that restores the original callbacks, for use with Pool where the engine outlives the connection.
+//
+// The push is non-blocking: if events is full because ProcessAudio's send loop isn't keeping up (or has
+// already returned, e.g. after a stream.Send error), the event is dropped rather than blocking pv.Process,
+// which would otherwise stall the goroutine reading audio off the stream indefinitely.
+func attachEventCallbacks(pv *picovoice.Picovoice, events chan *picovoicepb.Event) (restore func()) {
+	originalWakeWords := append([]picovoice.WakeWordCallbackType(nil), pv.WakeWordCallbacks...)
+	originalInferences := append([]picovoice.InferenceCallbackType(nil), pv.InferenceCallbacks...)
+
+	for i := range pv.WakeWordCallbacks {
+		index, original := i, originalWakeWords[i]
+		pv.WakeWordCallbacks[i] = func() {
+			if original != nil {
+				original()
+			}
+			select {
+			case events <- &picovoicepb.Event{Event: &picovoicepb.Event_WakeWordDetected{
+				WakeWordDetected: &picovoicepb.WakeWordDetected{KeywordIndex: int32(index)},
+			}}:
+			default:
+			}
+		}
+	}
+
+	for i := range pv.InferenceCallbacks {
+		original := originalInferences[i]
+		pv.InferenceCallbacks[i] = func(inference rhn.RhinoInference) {
+			if original != nil {
+				original(inference)
+			}
+			select {
+			case events <- &picovoicepb.Event{Event: &picovoicepb.Event_Inference{
+				Inference: &picovoicepb.Inference{
+					IsUnderstood: inference.IsUnderstood,
+					Intent:       inference.Intent,
+					Slots:        inference.Slots,
+				},
+			}}:
+			default:
+			}
+		}
+	}
+
+	return func() {
+		copy(pv.WakeWordCallbacks, originalWakeWords)
+		copy(pv.InferenceCallbacks, originalInferences)
+	}
+}
+
+// samplesToInt16 narrows the wire representation (int32, since protobuf has no native 16-bit integer) back to
+// the int16 frame Process expects.
+func samplesToInt16(samples []int32) []int16 {
+	pcm := make([]int16, len(samples))
+	for i, s := range samples {
+		pcm[i] = int16(s)
+	}
+	return pcm
+}
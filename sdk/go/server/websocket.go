@@ -0,0 +1,135 @@
+package server
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Picovoice/picovoice/sdk/go"
+	rhn "github.com/Picovoice/rhino/binding/go"
+	"github.com/gorilla/websocket"
+)
+
+// WSEvent is the JSON counterpart of picovoicepb.Event for the WebSocket transport: exactly one of the two
+// fields is populated per message.
+type WSEvent struct {
+	WakeWordDetected *WSWakeWordDetected `json:"wakeWordDetected,omitempty"`
+	Inference        *WSInference        `json:"inference,omitempty"`
+}
+
+type WSWakeWordDetected struct {
+	KeywordIndex int `json:"keywordIndex"`
+}
+
+type WSInference struct {
+	IsUnderstood bool              `json:"isUnderstood"`
+	Intent       string            `json:"intent"`
+	Slots        map[string]string `json:"slots"`
+}
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// WebSocketHandler accepts raw 16kHz mono int16 PCM frames over a WebSocket binary message per frame, and
+// emits WSEvent JSON text messages as Picovoice detects wake words and finalizes inferences. It implements
+// http.Handler so it can be mounted directly on an *http.ServeMux.
+type WebSocketHandler struct {
+	// NewPicovoice constructs a Picovoice configured for this deployment. Called once per connection.
+	NewPicovoice func() *picovoice.Picovoice
+}
+
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	pv := h.NewPicovoice()
+	events := make(chan WSEvent, eventBacklog)
+	attachWSEventCallbacks(pv, events)
+
+	if err := pv.Init(); err != nil {
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"error":"`+err.Error()+`"}`))
+		return
+	}
+	defer pv.Delete()
+
+	go h.pumpEvents(conn, events)
+	h.readFrames(conn, pv)
+
+	// readFrames only returns once no more Process calls (and therefore no more callback-driven sends on
+	// events) are in flight, since both run synchronously on this goroutine. Closing events here is what lets
+	// pumpEvents's range loop terminate instead of leaking a goroutine per connection.
+	close(events)
+}
+
+func (h *WebSocketHandler) readFrames(conn *websocket.Conn, pv *picovoice.Picovoice) {
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType != websocket.BinaryMessage || len(data) != picovoice.FrameLength*2 {
+			continue
+		}
+
+		frame := make([]int16, picovoice.FrameLength)
+		for i := range frame {
+			frame[i] = int16(binary.LittleEndian.Uint16(data[i*2 : i*2+2]))
+		}
+
+		if err := pv.Process(frame); err != nil {
+			return
+		}
+	}
+}
+
+func (h *WebSocketHandler) pumpEvents(conn *websocket.Conn, events chan WSEvent) {
+	for event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+			return
+		}
+	}
+}
+
+// attachWSEventCallbacks wraps pv's callbacks to also push a WSEvent for each detection/inference. The push is
+// non-blocking: a client that stops reading fills events and further events are dropped rather than blocking
+// pv.Process, which would otherwise stall readFrames (and the whole connection) indefinitely.
+func attachWSEventCallbacks(pv *picovoice.Picovoice, events chan WSEvent) {
+	for i := range pv.WakeWordCallbacks {
+		index, original := i, pv.WakeWordCallbacks[i]
+		pv.WakeWordCallbacks[i] = func() {
+			if original != nil {
+				original()
+			}
+			select {
+			case events <- WSEvent{WakeWordDetected: &WSWakeWordDetected{KeywordIndex: index}}:
+			default:
+			}
+		}
+	}
+
+	for i := range pv.InferenceCallbacks {
+		original := pv.InferenceCallbacks[i]
+		pv.InferenceCallbacks[i] = func(inference rhn.RhinoInference) {
+			if original != nil {
+				original(inference)
+			}
+			select {
+			case events <- WSEvent{Inference: &WSInference{
+				IsUnderstood: inference.IsUnderstood,
+				Intent:       inference.Intent,
+				Slots:        inference.Slots,
+			}}:
+			default:
+			}
+		}
+	}
+}
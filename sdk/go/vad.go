@@ -0,0 +1,117 @@
+package picovoice
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	webrtcvad "github.com/maxhawkins/go-webrtcvad"
+)
+
+// pcmToBytes converts a frame of 16-bit linearly-encoded samples into the little-endian byte slice the VAD
+// library expects.
+func pcmToBytes(pcm []int16) []byte {
+	raw := make([]byte, len(pcm)*2)
+	for i, sample := range pcm {
+		binary.LittleEndian.PutUint16(raw[i*2:i*2+2], uint16(sample))
+	}
+	return raw
+}
+
+// initVAD lazily constructs the VAD instance and preroll ring buffer the first time EnableVAD is used.
+func (picovoice *Picovoice) initVAD() error {
+	if picovoice.vad != nil {
+		return nil
+	}
+
+	if picovoice.VADAggressiveness < 0 || picovoice.VADAggressiveness > 3 {
+		return fmt.Errorf("%s: VADAggressiveness must be within [0, 3], got %d",
+			pvStatusToString(INVALID_ARGUMENT), picovoice.VADAggressiveness)
+	}
+
+	vad, err := webrtcvad.New()
+	if err != nil {
+		return err
+	}
+	if err := vad.SetMode(picovoice.VADAggressiveness); err != nil {
+		return err
+	}
+
+	picovoice.vad = vad
+	picovoice.vadPreroll = make([][]int16, 0, picovoice.VADPrerollFrames)
+	return nil
+}
+
+// vadSubFrameSamples is the length, in samples, of the 10ms sub-frames webrtcvad.Process actually accepts (it
+// only supports 10/20/30ms frames). Porcupine's FrameLength (512 samples, 32ms @ 16kHz) doesn't divide evenly
+// into any of those, so a whole Porcupine frame is fed through the detector as several 10ms sub-frames.
+func vadSubFrameSamples() int {
+	return SampleRate / 100
+}
+
+// vadActive re-chunks pcm into vadSubFrameSamples-sized sub-frames (zero-padding a trailing partial one) and
+// runs each through the detector, reporting active if any sub-frame is classified as speech. Erring toward
+// "active" on a mixed frame avoids clipping the very onset of the wake word.
+func (picovoice *Picovoice) vadActive(pcm []int16) (bool, error) {
+	subFrameLen := vadSubFrameSamples()
+	active := false
+
+	for start := 0; start < len(pcm); start += subFrameLen {
+		end := start + subFrameLen
+		subFrame := pcm[start:min(end, len(pcm))]
+		if len(subFrame) < subFrameLen {
+			padded := make([]int16, subFrameLen)
+			copy(padded, subFrame)
+			subFrame = padded
+		}
+
+		subActive, err := picovoice.vad.Process(SampleRate, pcmToBytes(subFrame))
+		if err != nil {
+			return false, err
+		}
+		if subActive {
+			active = true
+		}
+	}
+	return active, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// filterVAD is called on every frame while Picovoice is listening for a wake word (i.e. before Porcupine ever
+// sees the frame). It returns gate=true when the frame should be skipped because it was classified as
+// non-speech, and flush is the buffered preroll audio (oldest first) to replay through Porcupine the moment
+// speech resumes, so the very onset of the wake word isn't clipped by the gate.
+func (picovoice *Picovoice) filterVAD(pcm []int16) (gate bool, flush [][]int16, err error) {
+	if err := picovoice.initVAD(); err != nil {
+		return false, nil, err
+	}
+
+	active, err := picovoice.vadActive(pcm)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if !active {
+		if picovoice.VADPrerollFrames > 0 {
+			frame := make([]int16, len(pcm))
+			copy(frame, pcm)
+
+			picovoice.vadPreroll = append(picovoice.vadPreroll, frame)
+			if len(picovoice.vadPreroll) > picovoice.VADPrerollFrames {
+				picovoice.vadPreroll = picovoice.vadPreroll[1:]
+			}
+		}
+		return true, nil, nil
+	}
+
+	if len(picovoice.vadPreroll) > 0 {
+		flush = picovoice.vadPreroll
+		picovoice.vadPreroll = nil
+	}
+	return false, flush, nil
+}
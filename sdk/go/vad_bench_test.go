@@ -0,0 +1,52 @@
+package picovoice
+
+import "testing"
+
+// vadBenchSink defeats compiler dead-code elimination of the loop bodies below, so the benchmarks measure real
+// work rather than being optimized away.
+var vadBenchSink bool
+
+// gateFrame runs the same EnableVAD branch Process does (see processLocked) in front of where Porcupine would
+// otherwise see pcm. A genuine end-to-end comparison would run Process itself in both configurations, but that
+// needs Porcupine/Rhino model files this tree doesn't ship (and CI doesn't have), so the benchmarks below
+// compare against this gating decision instead of the full pipeline.
+func gateFrame(picovoice *Picovoice, pcm []int16, enableVAD bool) (gate bool, err error) {
+	if !enableVAD {
+		return false, nil
+	}
+	gate, _, err = picovoice.filterVAD(pcm)
+	return gate, err
+}
+
+// BenchmarkVADOff is the baseline: with EnableVAD false, every frame skips the gating branch entirely and
+// would go straight to Porcupine.
+func BenchmarkVADOff(b *testing.B) {
+	picovoice := &Picovoice{}
+	pcm := make([]int16, FrameLength)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gate, err := gateFrame(picovoice, pcm, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+		vadBenchSink = gate
+	}
+}
+
+// BenchmarkVADOn measures the cost of gating a long silent stream through filterVAD before it would reach
+// Porcupine, which is the scenario EnableVAD is meant to cut idle CPU on. It exercises the real detector via
+// vadActive, not a stub.
+func BenchmarkVADOn(b *testing.B) {
+	picovoice := &Picovoice{VADAggressiveness: 2, VADPrerollFrames: 10}
+	pcm := make([]int16, FrameLength) // silence
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gate, err := gateFrame(picovoice, pcm, true)
+		if err != nil {
+			b.Fatal(err)
+		}
+		vadBenchSink = gate
+	}
+}